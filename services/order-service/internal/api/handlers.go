@@ -0,0 +1,298 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ecommerce/order-service/internal/cache"
+	"github.com/ecommerce/order-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// jwtMiddleware validates the Bearer token and stashes the subject claim as
+// "userID" in the Gin context. Same JWT secret as user-service — tokens
+// issued there are valid here.
+func (s *Server) jwtMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" || len(authHeader) < 8 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing token"})
+			return
+		}
+		tokenStr := authHeader[7:] // strip "Bearer "
+		token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method")
+			}
+			return []byte(s.jwtSecret), nil
+		})
+		if err != nil || !token.Valid {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+		claims := token.Claims.(jwt.MapClaims)
+		c.Set("userID", int(claims["sub"].(float64)))
+
+		role, _ := claims["role"].(string)
+		if role == "" {
+			role = "user"
+		}
+		c.Set("role", role)
+
+		c.Next()
+	}
+}
+
+const ordersPageSize = 20
+
+// listOrders serves GET /orders.
+//
+// Breaking change: this endpoint used to return every order for the
+// caller in one response; it now returns ordersPageSize (20) most-recent
+// orders per call, selected via the "page" query param (1-based, default
+// 1). The switch was required by the cache layer — OrdersListKey caches
+// one page at a time, so an unbounded result couldn't be cached or
+// invalidated cheaply. Callers that relied on getting the full order
+// history in one request must now page through with ?page=2, ?page=3, etc.
+func (s *Server) listOrders(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := c.Request.Context()
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	body, err := s.cache.GetOrSet(ctx, cache.OrdersListKey(userID, page), "list_orders", func() ([]byte, error) {
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT id, user_id, product_id, quantity, total_price, status, created_at, updated_at
+			 FROM orders WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`,
+			userID, ordersPageSize, (page-1)*ordersPageSize)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		orders := []domain.Order{}
+		for rows.Next() {
+			var o domain.Order
+			if err := rows.Scan(&o.ID, &o.UserID, &o.ProductID, &o.Quantity, &o.TotalPrice, &o.Status, &o.CreatedAt, &o.UpdatedAt); err != nil {
+				return nil, err
+			}
+			orders = append(orders, o)
+		}
+		return json.Marshal(orders)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+func (s *Server) createOrder(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := c.Request.Context()
+
+	rawBody, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(rawBody)) // restore for ShouldBindJSON below
+
+	var req domain.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// ── Idempotency-Key (RFC-draft semantics) ───────────────────
+	// A client retrying the same POST /orders (e.g. after a timeout) with
+	// the same key gets back the original response instead of creating a
+	// second order.
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	if idempotencyKey != "" {
+		bodyHash := sha256.Sum256(rawBody)
+		outcome, status, body, err := s.claimIdempotencyKey(ctx, userID, idempotencyKey, hex.EncodeToString(bodyHash[:]))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		switch outcome {
+		case idempotencyReplay:
+			c.Data(status, "application/json; charset=utf-8", body)
+			return
+		case idempotencyMismatch:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request body"})
+			return
+		case idempotencyInFlight:
+			c.JSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is already in progress"})
+			return
+		}
+		// idempotencyProceed falls through to create the order below.
+	}
+
+	// failCreate responds 500 and, if a key was claimed above, releases it so
+	// a retry isn't rejected as idempotencyInFlight for a request that never
+	// actually created an order (only call this before tx.Commit succeeds —
+	// once an order exists the claim must stay to protect it from a replay).
+	failCreate := func(err error) {
+		if idempotencyKey != "" {
+			if rerr := s.releaseIdempotencyKey(ctx, userID, idempotencyKey); rerr != nil {
+				s.logger.Warn("failed to release idempotency key after error", zap.Error(rerr))
+			}
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		failCreate(err)
+		return
+	}
+	defer tx.Rollback() // no-op once committed
+
+	var order domain.Order
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO orders (user_id, product_id, quantity, total_price, status, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, 'pending', NOW(), NOW())
+		 RETURNING id, user_id, product_id, quantity, total_price, status, created_at, updated_at`,
+		userID, req.ProductID, req.Quantity, req.TotalPrice,
+	).Scan(&order.ID, &order.UserID, &order.ProductID, &order.Quantity, &order.TotalPrice,
+		&order.Status, &order.CreatedAt, &order.UpdatedAt)
+
+	if err != nil {
+		failCreate(err)
+		return
+	}
+
+	// ── Outbox insert (same transaction as the order write) ────
+	// notification-service subscribes to "order.created" and sends emails/webhooks.
+	// Writing the event here, in the same transaction as the order row,
+	// guarantees the event survives even if the broker is down at publish
+	// time — the outbox dispatcher retries until it lands.
+	payload, err := json.Marshal(map[string]interface{}{
+		"event":       "order.created",
+		"order_id":    order.ID,
+		"user_id":     order.UserID,
+		"product_id":  order.ProductID,
+		"total_price": order.TotalPrice,
+	})
+	if err != nil {
+		failCreate(err)
+		return
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO order_events_outbox (aggregate_id, event_type, payload) VALUES ($1, $2, $3)`,
+		order.ID, "order.created", payload,
+	); err != nil {
+		failCreate(err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		failCreate(err)
+		return
+	}
+
+	// Best-effort live update for the WebSocket channel — not outbox-backed;
+	// a missed WS push just means the client falls back to polling GET /orders.
+	go s.publishOrderEvent("order.created", order)
+
+	// New orders always land on page 1 (ORDER BY created_at DESC); older
+	// pages are untouched and simply expire on their own TTL.
+	s.cache.Invalidate(ctx, cache.OrdersListKey(userID, 1))
+
+	respBody, err := json.Marshal(order)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if idempotencyKey != "" {
+		if err := s.recordIdempotentResponse(ctx, userID, idempotencyKey, http.StatusCreated, respBody); err != nil {
+			s.logger.Warn("failed to record idempotent response", zap.Error(err))
+		}
+	}
+
+	c.Data(http.StatusCreated, "application/json; charset=utf-8", respBody)
+}
+
+func (s *Server) getOrder(c *gin.Context) {
+	userID := c.GetInt("userID")
+	ctx := c.Request.Context()
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+
+	// The cache key isn't scoped by user (an order belongs to exactly one
+	// user, so its content never differs by requester), so ownership is
+	// checked on every request below — cache hit or miss alike.
+	body, err := s.cache.GetOrSet(ctx, cache.OrderKey(orderID), "get_order", func() ([]byte, error) {
+		var order domain.Order
+		err := s.db.QueryRowContext(ctx,
+			`SELECT id, user_id, product_id, quantity, total_price, status, created_at, updated_at
+			 FROM orders WHERE id = $1`, orderID,
+		).Scan(&order.ID, &order.UserID, &order.ProductID, &order.Quantity, &order.TotalPrice,
+			&order.Status, &order.CreatedAt, &order.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(order)
+	})
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var order domain.Order
+	if err := json.Unmarshal(body, &order); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if order.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// publishOrderEvent fans an order lifecycle event out to the per-user topic
+// (order.events.user.<userID>) that the WebSocket subscriber relays to
+// connected browser clients.
+func (s *Server) publishOrderEvent(eventType string, order domain.Order) {
+	event := domain.Event{
+		Type:    eventType,
+		OrderID: order.ID,
+		UserID:  order.UserID,
+		Status:  order.Status,
+		Ts:      time.Now(),
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		s.logger.Warn("failed to marshal order event", zap.String("type", eventType), zap.Error(err))
+		return
+	}
+	topic := fmt.Sprintf("order.events.user.%d", order.UserID)
+	if err := s.publisher.Publish(context.Background(), topic, payload); err != nil {
+		s.logger.Warn("failed to publish order event", zap.String("topic", topic), zap.Error(err))
+	}
+}
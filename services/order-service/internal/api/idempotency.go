@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+)
+
+// idempotencyTTL bounds how long a claimed Idempotency-Key protects against
+// a duplicate POST /orders; past this window the key can be reused for a
+// brand new request.
+const idempotencyTTL = 24 * 60 * 60 // seconds, kept as an int for the SQL interval below
+
+// idempotencyOutcome is what createOrder should do once an Idempotency-Key
+// header is present.
+type idempotencyOutcome int
+
+const (
+	idempotencyProceed idempotencyOutcome = iota // caller owns the key, go create the order
+	idempotencyReplay                            // a prior response is stored — re-send it verbatim
+	idempotencyMismatch                          // same key, different body — 422
+	idempotencyInFlight                          // same key, still being processed by another request — 409
+)
+
+// claimIdempotencyKey atomically claims (user_id, key) for this request using
+// INSERT ... ON CONFLICT, so concurrent retries racing on the same key can't
+// both create an order. If the existing row is older than idempotencyTTL the
+// claim is refreshed (treated as if the key were new).
+func (s *Server) claimIdempotencyKey(ctx context.Context, userID int, key, bodyHash string) (idempotencyOutcome, int, []byte, error) {
+	var inserted bool
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (user_id, key, body_hash, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, key) DO UPDATE
+			SET body_hash = EXCLUDED.body_hash, created_at = NOW(), response_status = NULL, response_body = NULL
+			WHERE idempotency_keys.created_at < NOW() - make_interval(secs => $4)
+		RETURNING (xmax = 0)`,
+		userID, key, bodyHash, idempotencyTTL,
+	).Scan(&inserted)
+
+	if err == nil {
+		// We own the claim, fresh or just reset after expiry.
+		return idempotencyProceed, 0, nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, 0, nil, err
+	}
+
+	// No row returned: a live (non-expired) claim already exists. Look it up
+	// to decide whether this is a replay, a conflicting body, or a request
+	// still in flight.
+	var existingHash string
+	var status sql.NullInt64
+	var body []byte
+	err = s.db.QueryRowContext(ctx,
+		`SELECT body_hash, response_status, response_body FROM idempotency_keys WHERE user_id = $1 AND key = $2`,
+		userID, key,
+	).Scan(&existingHash, &status, &body)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	if existingHash != bodyHash {
+		return idempotencyMismatch, 0, nil, nil
+	}
+	if !status.Valid {
+		return idempotencyInFlight, 0, nil, nil
+	}
+	return idempotencyReplay, int(status.Int64), body, nil
+}
+
+// recordIdempotentResponse stores the response against the claimed key so
+// the next request with the same (user_id, key) replays it instead of
+// re-running createOrder.
+func (s *Server) recordIdempotentResponse(ctx context.Context, userID int, key string, status int, body []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE idempotency_keys SET response_status = $1, response_body = $2 WHERE user_id = $3 AND key = $4`,
+		status, body, userID, key)
+	return err
+}
+
+// releaseIdempotencyKey deletes a claimed key after createOrder fails before
+// committing an order. Without this, the row is left in the "in-flight"
+// state (NULL response_status) until idempotencyTTL expires, so every retry
+// with the same key gets rejected as idempotencyInFlight even though no
+// order was ever created.
+func (s *Server) releaseIdempotencyKey(ctx context.Context, userID int, key string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2`,
+		userID, key)
+	return err
+}
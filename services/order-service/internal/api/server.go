@@ -0,0 +1,110 @@
+// Package api wires order-service's HTTP surface: the Gin router, JWT
+// middleware, REST handlers, and the WebSocket push channel. Handlers depend
+// on events.Publisher, not a concrete broker client, so they work the same
+// way regardless of which EVENT_BUS is selected.
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sync"
+
+	"github.com/ecommerce/order-service/internal/cache"
+	"github.com/ecommerce/order-service/internal/config"
+	"github.com/ecommerce/order-service/internal/events"
+	"github.com/ecommerce/order-service/internal/outbox"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+// Server holds every dependency the HTTP handlers need. One instance per
+// process, constructed by the DI container.
+type Server struct {
+	db        *sql.DB
+	publisher events.Publisher
+	outbox    *outbox.Dispatcher
+	cache     *cache.Cache
+	jwtSecret string
+	logger    *zap.Logger
+
+	httpRequests *prometheus.CounterVec
+
+	// wsClients maps userID -> connected *wsClient slice; wsClientsMu
+	// guards the read-modify-write register/unregister pair against each
+	// other (sync.Map's own atomics aren't enough for that). broadcastToUser
+	// reads without the lock, so register/unregister must never mutate a
+	// slice in place — always store a freshly built one.
+	wsClients   sync.Map
+	wsClientsMu sync.Mutex
+}
+
+// NewServer constructs a Server and registers its Prometheus metrics.
+func NewServer(database *sql.DB, publisher events.Publisher, dispatcher *outbox.Dispatcher, orderCache *cache.Cache, cfg *config.Config, logger *zap.Logger) *Server {
+	httpRequests := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "order_service_http_requests_total",
+			Help: "Total HTTP requests to order-service",
+		},
+		[]string{"method", "path", "status"},
+	)
+	prometheus.MustRegister(httpRequests)
+
+	return &Server{
+		db:           database,
+		publisher:    publisher,
+		outbox:       dispatcher,
+		cache:        orderCache,
+		jwtSecret:    cfg.JWTSecret,
+		logger:       logger,
+		httpRequests: httpRequests,
+	}
+}
+
+// Router builds the Gin engine and registers all routes.
+func (s *Server) Router() *gin.Engine {
+	if gin.Mode() == "" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	r := gin.New()
+	r.Use(gin.Logger(), gin.Recovery())
+
+	r.GET("/health", func(c *gin.Context) {
+		status, code := "ok", http.StatusOK
+		if err := s.db.PingContext(c.Request.Context()); err != nil {
+			status, code = "error", http.StatusServiceUnavailable
+		}
+
+		outboxHealthy, err := s.outbox.Healthy(c.Request.Context())
+		if err != nil || !outboxHealthy {
+			status, code = "degraded", http.StatusServiceUnavailable
+		}
+
+		c.JSON(code, gin.H{"status": status, "service": "order-service"})
+	})
+
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	orders := r.Group("/orders", s.jwtMiddleware())
+	{
+		orders.GET("", s.listOrders)
+		orders.POST("", s.createOrder)
+		orders.GET("/:id", s.getOrder)
+		orders.GET("/ws", s.handleOrdersWS)
+		orders.PATCH("/:id/status", s.updateOrderStatus)
+		orders.POST("/:id/cancel", s.cancelOrder)
+		orders.GET("/:id/history", s.getOrderHistory)
+	}
+
+	return r
+}
+
+// StartEventSubscriber begins relaying per-user events onto connected
+// WebSocket clients. Run from main.go's fx.Lifecycle OnStart hook, alongside
+// the HTTP server; stops when ctx is cancelled at shutdown.
+func (s *Server) StartEventSubscriber(ctx context.Context) {
+	go s.subscribeOrderEvents(ctx)
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/ecommerce/order-service/internal/cache"
+	"github.com/ecommerce/order-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateOrderStatusRequest is the PATCH /orders/:id/status payload.
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// CancelOrderRequest is the POST /orders/:id/cancel payload.
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (s *Server) updateOrderStatus(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+
+	var req UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if domain.RequiresAdmin(req.Status) && c.GetString("role") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin role required for this transition"})
+		return
+	}
+
+	s.transitionOrder(c, orderID, req.Status, req.Reason)
+}
+
+func (s *Server) cancelOrder(c *gin.Context) {
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+
+	var req CancelOrderRequest
+	_ = c.ShouldBindJSON(&req) // body is optional — a missing/empty reason is fine
+
+	s.transitionOrder(c, orderID, domain.StatusCancelled, req.Reason)
+}
+
+// transitionOrder validates and applies a status change: it locks the order
+// row, checks the state machine, updates the status, records the audit
+// trail row, and — on success — emits order.status_changed (or
+// order.cancelled) for the WebSocket channel.
+func (s *Server) transitionOrder(c *gin.Context, orderID int, to, reason string) {
+	actorUserID := c.GetInt("userID")
+	ctx := c.Request.Context()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer tx.Rollback() // no-op once committed
+
+	var order domain.Order
+	err = tx.QueryRowContext(ctx,
+		`SELECT id, user_id, product_id, quantity, total_price, status, created_at, updated_at
+		 FROM orders WHERE id = $1 FOR UPDATE`, orderID,
+	).Scan(&order.ID, &order.UserID, &order.ProductID, &order.Quantity, &order.TotalPrice,
+		&order.Status, &order.CreatedAt, &order.UpdatedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Same ownership rule as getOrderHistory: a transition belongs to the
+	// order's own user, except admin-gated transitions (ship/deliver) which
+	// only an admin may perform regardless of who placed the order.
+	if order.UserID != actorUserID && !(domain.RequiresAdmin(to) && c.GetString("role") == "admin") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	}
+
+	from := order.Status
+	if err := domain.CanTransition(from, to); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.QueryRowContext(ctx,
+		`UPDATE orders SET status = $1, updated_at = NOW() WHERE id = $2 RETURNING updated_at`,
+		to, orderID,
+	).Scan(&order.UpdatedAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	order.Status = to
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO order_status_history (order_id, from_status, to_status, actor_user_id, reason)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		orderID, from, to, actorUserID, reason,
+	); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	eventType := "order.status_changed"
+	if to == domain.StatusCancelled {
+		eventType = "order.cancelled"
+	}
+	go s.publishOrderEvent(eventType, order)
+
+	s.cache.Invalidate(ctx, cache.OrderKey(orderID), cache.OrdersListKey(order.UserID, 1))
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (s *Server) getOrderHistory(c *gin.Context) {
+	userID := c.GetInt("userID")
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var owner int
+	err = s.db.QueryRowContext(ctx, `SELECT user_id FROM orders WHERE id = $1`, orderID).Scan(&owner)
+	if err == sql.ErrNoRows || (err == nil && owner != userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, order_id, from_status, to_status, actor_user_id, reason, at
+		 FROM order_status_history WHERE order_id = $1 ORDER BY at ASC`, orderID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	history := []domain.StatusTransition{}
+	for rows.Next() {
+		var t domain.StatusTransition
+		var reason sql.NullString
+		if err := rows.Scan(&t.ID, &t.OrderID, &t.FromStatus, &t.ToStatus, &t.ActorUserID, &reason, &t.At); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		t.Reason = reason.String
+		history = append(history, t)
+	}
+	c.JSON(http.StatusOK, history)
+}
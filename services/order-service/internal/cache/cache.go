@@ -0,0 +1,129 @@
+// Package cache provides a Redis-backed read-through cache for order-service's
+// read endpoints. It's independent of internal/events — EVENT_BUS can be
+// "nats" or "kafka" and this cache still talks to Redis directly, since
+// caching is a storage concern, not a messaging one.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/order-service/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache wraps a Redis client with the read-through pattern used by
+// getOrder/listOrders: check Redis, fall back to Postgres on miss, write
+// back with a TTL. Disabled via CACHE_ENABLED, in which case GetOrSet always
+// calls through to compute.
+type Cache struct {
+	client  *redis.Client
+	enabled bool
+	ttl     time.Duration
+	group   singleflight.Group
+	logger  *zap.Logger
+
+	hits   *prometheus.CounterVec
+	misses *prometheus.CounterVec
+}
+
+// New dials Redis (even if EVENT_BUS points elsewhere) and registers the
+// cache hit/miss counters. Connection failures are logged, not fatal — like
+// the rest of this service, Redis is optional infrastructure; a cache that
+// can't be reached just behaves as if CACHE_ENABLED=false for that request.
+func New(cfg *config.Config, logger *zap.Logger) (*Cache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       0,
+	})
+	if cfg.Cache.Enabled {
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			logger.Warn("cache: cannot connect to redis, reads will bypass the cache", zap.Error(err))
+		}
+	}
+
+	hits := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "order_cache_hits_total",
+		Help: "Total cache hits for order-service read endpoints",
+	}, []string{"endpoint"})
+	misses := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "order_cache_misses_total",
+		Help: "Total cache misses for order-service read endpoints",
+	}, []string{"endpoint"})
+	prometheus.MustRegister(hits, misses)
+
+	ttl := cfg.Cache.TTL
+	if ttl <= 0 {
+		ttl = 60 * time.Second
+	}
+
+	return &Cache{
+		client:  client,
+		enabled: cfg.Cache.Enabled,
+		ttl:     ttl,
+		logger:  logger,
+		hits:    hits,
+		misses:  misses,
+	}, nil
+}
+
+// OrderKey is the cache key for a single order.
+func OrderKey(orderID int) string { return fmt.Sprintf("order:%d", orderID) }
+
+// OrdersListKey is the cache key for one page of a user's order list.
+func OrdersListKey(userID, page int) string { return fmt.Sprintf("orders:user:%d:page:%d", userID, page) }
+
+// GetOrSet returns the cached value for key, computing and caching it on a
+// miss. endpoint labels the hit/miss counters (e.g. "get_order",
+// "list_orders"). Concurrent misses for the same key are coalesced via
+// singleflight so a cold key under load only triggers one DB query.
+func (c *Cache) GetOrSet(ctx context.Context, key, endpoint string, compute func() ([]byte, error)) ([]byte, error) {
+	if !c.enabled {
+		return compute()
+	}
+
+	if val, err := c.client.Get(ctx, key).Bytes(); err == nil {
+		c.hits.WithLabelValues(endpoint).Inc()
+		return val, nil
+	} else if err != redis.Nil {
+		c.logger.Warn("cache: get failed, falling back to source", zap.String("key", key), zap.Error(err))
+	}
+	c.misses.WithLabelValues(endpoint).Inc()
+
+	val, err, _ := c.group.Do(key, func() (interface{}, error) {
+		data, err := compute()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.client.SetEx(ctx, key, data, c.ttl).Err(); err != nil {
+			c.logger.Warn("cache: set failed", zap.String("key", key), zap.Error(err))
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.([]byte), nil
+}
+
+// Invalidate deletes keys on write so the next read repopulates them.
+// No-op (and no Redis round trip) when the cache is disabled.
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) {
+	if !c.enabled || len(keys) == 0 {
+		return
+	}
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		c.logger.Warn("cache: invalidate failed", zap.Strings("keys", keys), zap.Error(err))
+	}
+}
+
+// Close releases the underlying Redis connection. Safe to call once during
+// shutdown (e.g. from an fx.Lifecycle OnStop hook).
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
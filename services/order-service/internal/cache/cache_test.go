@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// newTestCache builds a Cache against an in-process miniredis instance,
+// constructing the struct directly rather than via New so tests don't
+// collide on prometheus.MustRegister across test functions.
+func newTestCache(t *testing.T, enabled bool) *Cache {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	return &Cache{
+		client:  redis.NewClient(&redis.Options{Addr: mr.Addr()}),
+		enabled: enabled,
+		ttl:     time.Minute,
+		logger:  zap.NewNop(),
+		hits:    prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_cache_hits"}, []string{"endpoint"}),
+		misses:  prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_cache_misses"}, []string{"endpoint"}),
+	}
+}
+
+func TestGetOrSet_MissThenHit(t *testing.T) {
+	c := newTestCache(t, true)
+	ctx := context.Background()
+
+	computed := 0
+	compute := func() ([]byte, error) {
+		computed++
+		return []byte(`"value"`), nil
+	}
+
+	if _, err := c.GetOrSet(ctx, "k", "ep", compute); err != nil {
+		t.Fatalf("GetOrSet miss: %v", err)
+	}
+	if _, err := c.GetOrSet(ctx, "k", "ep", compute); err != nil {
+		t.Fatalf("GetOrSet hit: %v", err)
+	}
+	if computed != 1 {
+		t.Fatalf("compute called %d times, want 1 (second call should hit cache)", computed)
+	}
+}
+
+func TestGetOrSet_InvalidateRepopulates(t *testing.T) {
+	c := newTestCache(t, true)
+	ctx := context.Background()
+
+	computed := 0
+	compute := func() ([]byte, error) {
+		computed++
+		return []byte(`"value"`), nil
+	}
+
+	if _, err := c.GetOrSet(ctx, "k", "ep", compute); err != nil {
+		t.Fatalf("GetOrSet: %v", err)
+	}
+	c.Invalidate(ctx, "k")
+	if _, err := c.GetOrSet(ctx, "k", "ep", compute); err != nil {
+		t.Fatalf("GetOrSet after invalidate: %v", err)
+	}
+	if computed != 2 {
+		t.Fatalf("compute called %d times, want 2 (invalidate should force a recompute)", computed)
+	}
+}
+
+// TestCache_SoakConcurrentConsistency hammers a single key with concurrent
+// writers (each bumping a version counter in the "database" and invalidating
+// the cache) and readers (each doing a GetOrSet), then asserts that once the
+// writers are done and the key is invalidated one last time, the cache
+// reflects the final database value exactly — i.e. concurrent traffic never
+// leaves the cache permanently stuck on a stale value.
+func TestCache_SoakConcurrentConsistency(t *testing.T) {
+	c := newTestCache(t, true)
+	ctx := context.Background()
+	const key = "order:soak"
+
+	var mu sync.Mutex
+	version := 0
+	compute := func() ([]byte, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return json.Marshal(version)
+	}
+
+	const writers = 20
+	const bumpsPerWriter = 25
+	var wg sync.WaitGroup
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < bumpsPerWriter; j++ {
+				mu.Lock()
+				version++
+				mu.Unlock()
+				c.Invalidate(ctx, key)
+			}
+		}()
+	}
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < bumpsPerWriter; j++ {
+				if _, err := c.GetOrSet(ctx, key, "soak", compute); err != nil {
+					t.Errorf("GetOrSet during soak: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.Invalidate(ctx, key)
+	body, err := c.GetOrSet(ctx, key, "soak", compute)
+	if err != nil {
+		t.Fatalf("final GetOrSet: %v", err)
+	}
+	var got int
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unmarshal final value: %v", err)
+	}
+	mu.Lock()
+	want := version
+	mu.Unlock()
+	if got != want {
+		t.Fatalf("cache settled on stale value %d, want final db value %d", got, want)
+	}
+}
+
+// BenchmarkGetOrSet_CacheHit and BenchmarkGetOrSet_Disabled demonstrate the
+// throughput improvement the cache gives read endpoints: compute simulates
+// realistic Postgres latency, so a cache hit should be orders of magnitude
+// faster under load.
+func simulatedDBRead() ([]byte, error) {
+	time.Sleep(time.Millisecond)
+	return []byte(`{"id":1}`), nil
+}
+
+func BenchmarkGetOrSet_CacheHit(b *testing.B) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		b.Fatalf("miniredis.Run: %v", err)
+	}
+	defer mr.Close()
+
+	c := &Cache{
+		client:  redis.NewClient(&redis.Options{Addr: mr.Addr()}),
+		enabled: true,
+		ttl:     time.Minute,
+		logger:  zap.NewNop(),
+		hits:    prometheus.NewCounterVec(prometheus.CounterOpts{Name: "bench_cache_hits"}, []string{"endpoint"}),
+		misses:  prometheus.NewCounterVec(prometheus.CounterOpts{Name: "bench_cache_misses"}, []string{"endpoint"}),
+	}
+	ctx := context.Background()
+	key := fmt.Sprintf("bench:%d", 1)
+	if _, err := c.GetOrSet(ctx, key, "bench", simulatedDBRead); err != nil {
+		b.Fatalf("warm-up GetOrSet: %v", err)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.GetOrSet(ctx, key, "bench", simulatedDBRead); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetOrSet_Disabled(b *testing.B) {
+	c := &Cache{enabled: false, logger: zap.NewNop()}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.GetOrSet(ctx, "bench", "bench", simulatedDBRead); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,122 @@
+// Package config loads order-service's configuration into typed structs via
+// viper. Every field has an env var override so the service keeps working
+// unconfigured in dev (matching the getEnv(key, fallback) defaults the
+// service used before the DI refactor).
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config is the root configuration object, injected into the fx container
+// and handed to every provider that needs a setting.
+type Config struct {
+	Port      string
+	JWTSecret string
+
+	EventBus string // "redis" | "nats" | "kafka" — selects the events.Publisher implementation
+
+	Outbox OutboxConfig
+	Cache  CacheConfig
+
+	DB    DBConfig
+	Redis RedisConfig
+	NATS  NATSConfig
+	Kafka KafkaConfig
+}
+
+type DBConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+}
+
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Password string
+}
+
+type NATSConfig struct {
+	URL string
+}
+
+type KafkaConfig struct {
+	Brokers []string
+}
+
+type OutboxConfig struct {
+	PollInterval     time.Duration
+	PendingThreshold int // /health degrades once unpublished rows exceed this
+}
+
+type CacheConfig struct {
+	Enabled bool
+	TTL     time.Duration
+}
+
+// Load reads configuration from the process environment via viper's
+// AutomaticEnv. A .env file is not read here; godotenv.Load() in main copies
+// it into the process environment first, if present. Defaults match the values
+// the service used before the DI refactor.
+func Load() (*Config, error) {
+	v := viper.New()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	v.SetDefault("port", "8080")
+	v.SetDefault("jwt_secret", "")
+	v.SetDefault("event_bus", "redis")
+	v.SetDefault("db_host", "localhost")
+	v.SetDefault("db_port", "5432")
+	v.SetDefault("db_user", "postgres")
+	v.SetDefault("db_password", "")
+	v.SetDefault("db_name", "orders")
+	v.SetDefault("redis_host", "localhost")
+	v.SetDefault("redis_port", "6379")
+	v.SetDefault("redis_password", "")
+	v.SetDefault("nats_url", "nats://localhost:4222")
+	v.SetDefault("kafka_brokers", "localhost:9092")
+	v.SetDefault("outbox_poll_interval", "2s")
+	v.SetDefault("outbox_pending_threshold", 100)
+	v.SetDefault("cache_enabled", true)
+	v.SetDefault("cache_ttl", "60s")
+
+	cfg := &Config{
+		Port:      v.GetString("port"),
+		JWTSecret: v.GetString("jwt_secret"),
+		EventBus:  v.GetString("event_bus"),
+		Outbox: OutboxConfig{
+			PollInterval:     v.GetDuration("outbox_poll_interval"),
+			PendingThreshold: v.GetInt("outbox_pending_threshold"),
+		},
+		Cache: CacheConfig{
+			Enabled: v.GetBool("cache_enabled"),
+			TTL:     v.GetDuration("cache_ttl"),
+		},
+		DB: DBConfig{
+			Host:     v.GetString("db_host"),
+			Port:     v.GetString("db_port"),
+			User:     v.GetString("db_user"),
+			Password: v.GetString("db_password"),
+			Name:     v.GetString("db_name"),
+		},
+		Redis: RedisConfig{
+			Host:     v.GetString("redis_host"),
+			Port:     v.GetString("redis_port"),
+			Password: v.GetString("redis_password"),
+		},
+		NATS: NATSConfig{
+			URL: v.GetString("nats_url"),
+		},
+		Kafka: KafkaConfig{
+			Brokers: strings.Split(v.GetString("kafka_brokers"), ","),
+		},
+	}
+	return cfg, nil
+}
@@ -0,0 +1,85 @@
+// Package db owns the Postgres connection and schema for order-service.
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/order-service/internal/config"
+	_ "github.com/lib/pq" // PostgreSQL driver (blank import registers it)
+	"go.uber.org/zap"
+)
+
+// New opens the Postgres connection, pings it, and ensures the schema
+// exists. Returned *sql.DB is a pool — callers should not close individual
+// connections.
+func New(cfg *config.Config, logger *zap.Logger) (*sql.DB, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		cfg.DB.Host, cfg.DB.Port, cfg.DB.User, cfg.DB.Password, cfg.DB.Name)
+
+	sqlDB, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("db: open: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(10)
+	sqlDB.SetMaxIdleConns(5)
+	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+
+	if err := sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("db: ping: %w", err)
+	}
+
+	if _, err := sqlDB.Exec(`
+		CREATE TABLE IF NOT EXISTS orders (
+			id          SERIAL PRIMARY KEY,
+			user_id     INT NOT NULL,
+			product_id  VARCHAR(50) NOT NULL,
+			quantity    INT NOT NULL CHECK (quantity > 0),
+			total_price DECIMAL(10,2) NOT NULL CHECK (total_price > 0),
+			status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+			created_at  TIMESTAMPTZ DEFAULT NOW(),
+			updated_at  TIMESTAMPTZ DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_orders_user ON orders(user_id);
+
+		CREATE TABLE IF NOT EXISTS order_events_outbox (
+			id              BIGSERIAL PRIMARY KEY,
+			aggregate_id    INT NOT NULL,
+			event_type      VARCHAR(50) NOT NULL,
+			payload         JSONB NOT NULL,
+			created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			published_at    TIMESTAMPTZ,
+			attempts        INT NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON order_events_outbox (next_attempt_at) WHERE published_at IS NULL;
+
+		CREATE TABLE IF NOT EXISTS idempotency_keys (
+			id              BIGSERIAL PRIMARY KEY,
+			user_id         INT NOT NULL,
+			key             VARCHAR(255) NOT NULL,
+			body_hash       VARCHAR(64) NOT NULL,
+			response_status INT,
+			response_body   JSONB,
+			created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (user_id, key)
+		);
+
+		CREATE TABLE IF NOT EXISTS order_status_history (
+			id            BIGSERIAL PRIMARY KEY,
+			order_id      INT NOT NULL REFERENCES orders(id),
+			from_status   VARCHAR(20) NOT NULL,
+			to_status     VARCHAR(20) NOT NULL,
+			actor_user_id INT NOT NULL,
+			reason        TEXT,
+			at            TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_status_history_order ON order_status_history(order_id);
+	`); err != nil {
+		return nil, fmt.Errorf("db: schema init: %w", err)
+	}
+
+	logger.Info("db: schema initialised")
+	return sqlDB, nil
+}
@@ -0,0 +1,37 @@
+// Package domain holds the order-service's core types, independent of how
+// they're stored (Postgres), transported (JSON over HTTP/WS), or published
+// (Redis/NATS/Kafka). Nothing in this package imports gin, sql, or redis.
+package domain
+
+import "time"
+
+// Order is the order domain model.
+type Order struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"user_id"`
+	ProductID  string    `json:"product_id"` // MongoDB ObjectId (string)
+	Quantity   int       `json:"quantity"`
+	TotalPrice float64   `json:"total_price"`
+	Status     string    `json:"status"` // pending | confirmed | shipped | delivered | cancelled
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CreateOrderRequest is the payload for POST /orders.
+type CreateOrderRequest struct {
+	ProductID  string  `json:"product_id" binding:"required"`
+	Quantity   int     `json:"quantity"   binding:"required,min=1"`
+	TotalPrice float64 `json:"total_price" binding:"required,gt=0"`
+}
+
+// Event is the structured payload published on the per-user
+// "order.events.user.<userID>" topic. It is not sent on the "order.created"
+// broadcast topic, which carries the outbox payload (event, order_id,
+// user_id, product_id, total_price) instead.
+type Event struct {
+	Type    string    `json:"type"` // order.created | order.updated | order.status_changed | order.cancelled
+	OrderID int       `json:"order_id"`
+	UserID  int       `json:"user_id"`
+	Status  string    `json:"status"`
+	Ts      time.Time `json:"ts"`
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Order status values.
+const (
+	StatusPending   = "pending"
+	StatusConfirmed = "confirmed"
+	StatusShipped   = "shipped"
+	StatusDelivered = "delivered"
+	StatusCancelled = "cancelled"
+)
+
+// validTransitions is the order status state machine: pending -> confirmed
+// -> shipped -> delivered, with cancellation allowed from pending or
+// confirmed only (an order already shipped can't be cancelled).
+var validTransitions = map[string]map[string]bool{
+	StatusPending:   {StatusConfirmed: true, StatusCancelled: true},
+	StatusConfirmed: {StatusShipped: true, StatusCancelled: true},
+	StatusShipped:   {StatusDelivered: true},
+	StatusDelivered: {},
+	StatusCancelled: {},
+}
+
+// adminOnlyStatuses are transitions only an admin-role actor may perform —
+// shipping and delivery are fulfillment actions, not something the order's
+// own user can claim happened.
+var adminOnlyStatuses = map[string]bool{
+	StatusShipped:   true,
+	StatusDelivered: true,
+}
+
+// ErrIllegalTransition is returned by CanTransition when `to` isn't
+// reachable from `from`.
+type ErrIllegalTransition struct {
+	From, To string
+}
+
+func (e *ErrIllegalTransition) Error() string {
+	return fmt.Sprintf("cannot transition order from %q to %q", e.From, e.To)
+}
+
+// CanTransition reports whether an order may move from `from` to `to`.
+func CanTransition(from, to string) error {
+	if next, ok := validTransitions[from]; ok && next[to] {
+		return nil
+	}
+	return &ErrIllegalTransition{From: from, To: to}
+}
+
+// RequiresAdmin reports whether only an admin-role actor may set `to`.
+func RequiresAdmin(to string) bool {
+	return adminOnlyStatuses[to]
+}
+
+// StatusTransition is one row of an order's audit trail.
+type StatusTransition struct {
+	ID          int       `json:"id"`
+	OrderID     int       `json:"order_id"`
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	ActorUserID int       `json:"actor_user_id"`
+	Reason      string    `json:"reason,omitempty"`
+	At          time.Time `json:"at"`
+}
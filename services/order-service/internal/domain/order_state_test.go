@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{"pending to confirmed", StatusPending, StatusConfirmed, false},
+		{"pending to cancelled", StatusPending, StatusCancelled, false},
+		{"confirmed to shipped", StatusConfirmed, StatusShipped, false},
+		{"confirmed to cancelled", StatusConfirmed, StatusCancelled, false},
+		{"shipped to delivered", StatusShipped, StatusDelivered, false},
+		{"shipped to cancelled is illegal", StatusShipped, StatusCancelled, true},
+		{"delivered is terminal", StatusDelivered, StatusConfirmed, true},
+		{"cancelled is terminal", StatusCancelled, StatusConfirmed, true},
+		{"pending to shipped skips confirmed", StatusPending, StatusShipped, true},
+		{"unknown from status", "bogus", StatusConfirmed, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CanTransition(tc.from, tc.to)
+			if tc.wantErr && err == nil {
+				t.Fatalf("CanTransition(%q, %q) = nil, want error", tc.from, tc.to)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("CanTransition(%q, %q) = %v, want nil", tc.from, tc.to, err)
+			}
+			if tc.wantErr {
+				if _, ok := err.(*ErrIllegalTransition); !ok {
+					t.Fatalf("CanTransition(%q, %q) returned %T, want *ErrIllegalTransition", tc.from, tc.to, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRequiresAdmin(t *testing.T) {
+	for _, s := range []string{StatusShipped, StatusDelivered} {
+		if !RequiresAdmin(s) {
+			t.Errorf("RequiresAdmin(%q) = false, want true", s)
+		}
+	}
+	for _, s := range []string{StatusPending, StatusConfirmed, StatusCancelled} {
+		if RequiresAdmin(s) {
+			t.Errorf("RequiresAdmin(%q) = true, want false", s)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	kafka "github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// kafkaPublisher implements Publisher on top of Kafka. Unlike Redis/NATS,
+// Kafka topics don't support wildcard subscriptions — "*" isn't even a legal
+// character in a Kafka topic name — so Subscribe only works with exact topic
+// names and rejects a wildcard topic outright rather than quietly
+// subscribing to a literal "*" that will never receive anything.
+// order-service's only wildcard subscription is the WS per-user push
+// ("order.events.user.*" in internal/api/ws.go): under EVENT_BUS=kafka that
+// feature does not work and callers get this error at startup instead of a
+// channel that silently never delivers.
+type kafkaPublisher struct {
+	brokers []string
+	logger  *zap.Logger
+
+	mu      sync.Mutex
+	writers map[string]*kafka.Writer
+	readers []*kafka.Reader // tracked so Close can stop them
+}
+
+func NewKafkaPublisher(brokers []string, logger *zap.Logger) (Publisher, error) {
+	return &kafkaPublisher{
+		brokers: brokers,
+		logger:  logger,
+		writers: make(map[string]*kafka.Writer),
+	}, nil
+}
+
+func (p *kafkaPublisher) writerFor(topic string) *kafka.Writer {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if w, ok := p.writers[topic]; ok {
+		return w
+	}
+	w := &kafka.Writer{
+		Addr:     kafka.TCP(p.brokers...),
+		Topic:    topic,
+		Balancer: &kafka.LeastBytes{},
+	}
+	p.writers[topic] = w
+	return w
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	return p.writerFor(topic).WriteMessages(ctx, kafka.Message{Value: payload})
+}
+
+func (p *kafkaPublisher) Subscribe(ctx context.Context, topic string, handler func(payload []byte)) error {
+	if strings.Contains(topic, "*") {
+		return fmt.Errorf("events: kafka does not support wildcard subscriptions (topic %q); use EVENT_BUS=redis or EVENT_BUS=nats for the WS push channel", topic)
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: p.brokers,
+		Topic:   topic,
+		GroupID: "order-service",
+	})
+
+	p.mu.Lock()
+	p.readers = append(p.readers, reader)
+	p.mu.Unlock()
+
+	go func() {
+		for {
+			msg, err := reader.ReadMessage(ctx)
+			if err != nil {
+				return // context cancelled or reader closed
+			}
+			handler(msg.Value)
+		}
+	}()
+	return nil
+}
+
+func (p *kafkaPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, w := range p.writers {
+		w.Close()
+	}
+	for _, r := range p.readers {
+		r.Close()
+	}
+	return nil
+}
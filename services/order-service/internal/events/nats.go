@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// natsPublisher implements Publisher on top of NATS core pub/sub. NATS
+// subjects use the same "*" single-token wildcard syntax our topic strings
+// already use (e.g. "order.events.user.*"), so topics translate 1:1.
+type natsPublisher struct {
+	conn   *nats.Conn
+	logger *zap.Logger
+}
+
+func NewNATSPublisher(url string, logger *zap.Logger) (Publisher, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("nats: connected", zap.String("url", url))
+	return &natsPublisher{conn: conn, logger: logger}, nil
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	return p.conn.Publish(topic, payload)
+}
+
+func (p *natsPublisher) Subscribe(ctx context.Context, topic string, handler func(payload []byte)) error {
+	sub, err := p.conn.Subscribe(topic, func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		sub.Unsubscribe()
+	}()
+	return nil
+}
+
+func (p *natsPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
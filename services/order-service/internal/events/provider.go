@@ -0,0 +1,26 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/ecommerce/order-service/internal/config"
+	"go.uber.org/zap"
+)
+
+// NewPublisher selects a Publisher implementation based on cfg.EventBus.
+// This is the only place in the service that knows all three brokers exist —
+// everything downstream (handlers, the WS subscriber goroutine) depends on
+// the Publisher interface.
+func NewPublisher(cfg *config.Config, logger *zap.Logger) (Publisher, error) {
+	switch cfg.EventBus {
+	case "", "redis":
+		addr := fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port)
+		return NewRedisPublisher(addr, cfg.Redis.Password, logger)
+	case "nats":
+		return NewNATSPublisher(cfg.NATS.URL, logger)
+	case "kafka":
+		return NewKafkaPublisher(cfg.Kafka.Brokers, logger)
+	default:
+		return nil, fmt.Errorf("events: unknown EVENT_BUS %q (want redis|nats|kafka)", cfg.EventBus)
+	}
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/ecommerce/order-service/internal/config"
+	"go.uber.org/zap"
+)
+
+func TestNewPublisher(t *testing.T) {
+	logger := zap.NewNop()
+
+	cases := []struct {
+		name     string
+		eventBus string
+		wantErr  bool
+	}{
+		{"defaults to redis", "", false},
+		{"explicit redis", "redis", false},
+		{"kafka dials lazily", "kafka", false},
+		{"unknown bus is rejected", "rabbitmq", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config.Config{
+				EventBus: tc.eventBus,
+				Redis:    config.RedisConfig{Host: "localhost", Port: "6379"},
+				Kafka:    config.KafkaConfig{Brokers: []string{"localhost:9092"}},
+			}
+			pub, err := NewPublisher(cfg, logger)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("NewPublisher(%q) = nil error, want one", tc.eventBus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewPublisher(%q) = %v, want nil", tc.eventBus, err)
+			}
+			if pub == nil {
+				t.Fatalf("NewPublisher(%q) returned nil Publisher", tc.eventBus)
+			}
+		})
+	}
+}
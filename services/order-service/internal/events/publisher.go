@@ -0,0 +1,24 @@
+// Package events decouples order-service from any one message broker.
+// Handlers depend on the Publisher interface, not a concrete redis.Client,
+// so tests can inject a fake and the broker can be swapped via the
+// EVENT_BUS env var without touching business logic.
+package events
+
+import "context"
+
+// Publisher publishes to and subscribes on named topics. Topic strings are
+// broker-agnostic (e.g. "order.created", "order.events.user.42"); a "*"
+// segment is treated as a wildcard by brokers that support one (Redis, NATS).
+type Publisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+
+	// Subscribe registers handler to be called with the payload of every
+	// message received on topic. It returns once the subscription is
+	// established; handler runs on an internal goroutine for the lifetime
+	// of the Publisher (or until ctx is cancelled).
+	Subscribe(ctx context.Context, topic string, handler func(payload []byte)) error
+
+	// Close releases the underlying broker connection. Safe to call once
+	// during shutdown (e.g. from an fx.Lifecycle OnStop hook).
+	Close() error
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"context"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// redisPublisher is the default Publisher implementation — Redis Pub/Sub,
+// matching the order-service's behavior prior to the DI refactor.
+type redisPublisher struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewRedisPublisher dials Redis. Connection failures are logged, not fatal —
+// Redis has always been treated as optional infrastructure for this service
+// (orders still get written to Postgres in degraded mode).
+func NewRedisPublisher(addr, password string, logger *zap.Logger) (Publisher, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       0,
+	})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		logger.Warn("redis: cannot connect, events will not be published", zap.Error(err))
+	} else {
+		logger.Info("redis: connected")
+	}
+	return &redisPublisher{client: client, logger: logger}, nil
+}
+
+func (p *redisPublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	return p.client.Publish(ctx, topic, payload).Err()
+}
+
+func (p *redisPublisher) Subscribe(ctx context.Context, topic string, handler func(payload []byte)) error {
+	var sub *redis.PubSub
+	if strings.Contains(topic, "*") {
+		sub = p.client.PSubscribe(ctx, topic)
+	} else {
+		sub = p.client.Subscribe(ctx, topic)
+	}
+
+	go func() {
+		defer sub.Close()
+		for msg := range sub.Channel() {
+			handler([]byte(msg.Payload))
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		sub.Close() // closes sub.Channel(), ending the relay loop above
+	}()
+	return nil
+}
+
+func (p *redisPublisher) Close() error {
+	return p.client.Close()
+}
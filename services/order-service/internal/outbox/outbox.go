@@ -0,0 +1,184 @@
+// Package outbox implements the transactional outbox pattern for order
+// events: createOrder writes to the orders table and order_events_outbox in
+// the same transaction, and Dispatcher polls the outbox and publishes rows
+// that haven't made it to the broker yet. This guarantees an event is never
+// lost just because the broker was unreachable at the moment the order was
+// created — it gets retried until it is.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/order-service/internal/config"
+	"github.com/ecommerce/order-service/internal/events"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	batchSize  = 50
+	baseDelay  = 1 * time.Second
+	maxBackoff = 5 * time.Minute
+)
+
+// Dispatcher polls order_events_outbox for unpublished rows and publishes
+// them via events.Publisher.
+type Dispatcher struct {
+	db           *sql.DB
+	publisher    events.Publisher
+	logger       *zap.Logger
+	pollInterval time.Duration
+	threshold    int
+
+	pending     prometheus.Gauge
+	failedTotal prometheus.Counter
+}
+
+// NewDispatcher constructs a Dispatcher and registers its Prometheus metrics.
+func NewDispatcher(database *sql.DB, publisher events.Publisher, cfg *config.Config, logger *zap.Logger) *Dispatcher {
+	pending := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "order_outbox_pending",
+		Help: "Number of order_events_outbox rows not yet published",
+	})
+	failedTotal := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "order_outbox_failed_total",
+		Help: "Total publish attempts that failed and were rescheduled with backoff",
+	})
+	prometheus.MustRegister(pending, failedTotal)
+
+	pollInterval := cfg.Outbox.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = 2 * time.Second
+	}
+	threshold := cfg.Outbox.PendingThreshold
+	if threshold <= 0 {
+		threshold = 100
+	}
+
+	return &Dispatcher{
+		db:           database,
+		publisher:    publisher,
+		logger:       logger,
+		pollInterval: pollInterval,
+		threshold:    threshold,
+		pending:      pending,
+		failedTotal:  failedTotal,
+	}
+}
+
+// Run polls and dispatches on pollInterval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.dispatchBatch(ctx); err != nil {
+				d.logger.Warn("outbox: dispatch batch failed", zap.Error(err))
+			}
+			d.refreshPending(ctx)
+		}
+	}
+}
+
+type outboxRow struct {
+	id        int64
+	eventType string
+	payload   []byte
+	attempts  int
+}
+
+// dispatchBatch claims up to batchSize unpublished rows with
+// FOR UPDATE SKIP LOCKED (so multiple order-service replicas can run the
+// dispatcher concurrently without double-publishing) and publishes each.
+func (d *Dispatcher) dispatchBatch(ctx context.Context) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("outbox: begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, event_type, payload, attempts
+		FROM order_events_outbox
+		WHERE published_at IS NULL AND next_attempt_at <= NOW()
+		ORDER BY id
+		FOR UPDATE SKIP LOCKED
+		LIMIT $1`, batchSize)
+	if err != nil {
+		return fmt.Errorf("outbox: query: %w", err)
+	}
+
+	var batch []outboxRow
+	for rows.Next() {
+		var r outboxRow
+		if err := rows.Scan(&r.id, &r.eventType, &r.payload, &r.attempts); err != nil {
+			rows.Close()
+			return fmt.Errorf("outbox: scan: %w", err)
+		}
+		batch = append(batch, r)
+	}
+	rows.Close()
+
+	for _, row := range batch {
+		if err := d.publisher.Publish(ctx, row.eventType, row.payload); err != nil {
+			attempts := row.attempts + 1
+			if _, uerr := tx.ExecContext(ctx,
+				`UPDATE order_events_outbox SET attempts = $1, next_attempt_at = NOW() + $2 WHERE id = $3`,
+				attempts, backoffFor(attempts), row.id); uerr != nil {
+				return fmt.Errorf("outbox: reschedule: %w", uerr)
+			}
+			d.failedTotal.Inc()
+			d.logger.Warn("outbox: publish failed, rescheduled", zap.Int64("id", row.id), zap.Int("attempts", attempts), zap.Error(err))
+			continue
+		}
+		if _, uerr := tx.ExecContext(ctx,
+			`UPDATE order_events_outbox SET published_at = NOW() WHERE id = $1`, row.id); uerr != nil {
+			return fmt.Errorf("outbox: mark published: %w", uerr)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// backoffFor returns an exponential backoff capped at maxBackoff.
+func backoffFor(attempts int) time.Duration {
+	d := baseDelay << attempts
+	if d <= 0 || d > maxBackoff { // left-shift overflow also lands here
+		return maxBackoff
+	}
+	return d
+}
+
+func (d *Dispatcher) refreshPending(ctx context.Context) {
+	count, err := d.PendingCount(ctx)
+	if err != nil {
+		d.logger.Warn("outbox: failed to refresh pending gauge", zap.Error(err))
+		return
+	}
+	d.pending.Set(float64(count))
+}
+
+// PendingCount returns the number of unpublished outbox rows. Used both for
+// the Prometheus gauge and the /health backlog sub-check.
+func (d *Dispatcher) PendingCount(ctx context.Context) (int, error) {
+	var count int
+	err := d.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM order_events_outbox WHERE published_at IS NULL`).Scan(&count)
+	return count, err
+}
+
+// Healthy reports whether the pending backlog is within the configured
+// threshold.
+func (d *Dispatcher) Healthy(ctx context.Context) (bool, error) {
+	count, err := d.PendingCount(ctx)
+	if err != nil {
+		return false, err
+	}
+	return count <= d.threshold, nil
+}
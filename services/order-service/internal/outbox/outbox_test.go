@@ -0,0 +1,122 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// noopGauge/noopCounter build unregistered metric instances so tests don't
+// collide with the ones NewDispatcher registers globally via prometheus.MustRegister.
+func noopGauge() prometheus.Gauge {
+	return prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_outbox_pending"})
+}
+
+func noopCounter() prometheus.Counter {
+	return prometheus.NewCounter(prometheus.CounterOpts{Name: "test_outbox_failed_total"})
+}
+
+// fakePublisher simulates a broker that is down for the first N Publish
+// calls (e.g. a Redis outage) and then recovers.
+type fakePublisher struct {
+	failures int
+	calls    int
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	p.calls++
+	if p.calls <= p.failures {
+		return errors.New("simulated broker outage")
+	}
+	return nil
+}
+
+func (p *fakePublisher) Subscribe(ctx context.Context, topic string, handler func(payload []byte)) error {
+	return nil
+}
+
+func (p *fakePublisher) Close() error { return nil }
+
+func newTestDispatcher(t *testing.T, pub *fakePublisher) (*Dispatcher, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &Dispatcher{
+		db:           db,
+		publisher:    pub,
+		logger:       zap.NewNop(),
+		pollInterval: time.Millisecond,
+		threshold:    100,
+		pending:      noopGauge(),
+		failedTotal:  noopCounter(),
+	}, mock
+}
+
+func TestDispatchBatch_RetriesAfterSimulatedOutage(t *testing.T) {
+	pub := &fakePublisher{failures: 1}
+	d, mock := newTestDispatcher(t, pub)
+
+	rowCols := []string{"id", "event_type", "payload", "attempts"}
+
+	// First poll: broker is down, publish fails, row gets rescheduled.
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, event_type, payload, attempts`).
+		WillReturnRows(sqlmock.NewRows(rowCols).AddRow(int64(1), "order.created", []byte(`{}`), 0))
+	mock.ExpectExec(`UPDATE order_events_outbox SET attempts`).
+		WithArgs(1, sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := d.dispatchBatch(context.Background()); err != nil {
+		t.Fatalf("dispatchBatch (outage): %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations after outage poll: %v", err)
+	}
+
+	// Second poll: broker has recovered, the same row now publishes and is
+	// marked published — the event is eventually delivered.
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id, event_type, payload, attempts`).
+		WillReturnRows(sqlmock.NewRows(rowCols).AddRow(int64(1), "order.created", []byte(`{}`), 1))
+	mock.ExpectExec(`UPDATE order_events_outbox SET published_at`).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := d.dispatchBatch(context.Background()); err != nil {
+		t.Fatalf("dispatchBatch (recovered): %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations after recovery poll: %v", err)
+	}
+	if pub.calls != 2 {
+		t.Fatalf("publisher called %d times, want 2 (one failed, one succeeded)", pub.calls)
+	}
+}
+
+func TestBackoffFor(t *testing.T) {
+	if got := backoffFor(1); got != baseDelay<<1 {
+		t.Errorf("backoffFor(1) = %v, want %v", got, baseDelay<<1)
+	}
+	if got := backoffFor(0); got != baseDelay {
+		t.Errorf("backoffFor(0) = %v, want %v", got, baseDelay)
+	}
+	if got := backoffFor(10); got != maxBackoff {
+		t.Errorf("backoffFor(10) = %v, want capped at %v", got, maxBackoff)
+	}
+	// Large attempts overflow the left shift; backoffFor must still cap
+	// rather than returning a negative or zero duration.
+	if got := backoffFor(100); got != maxBackoff {
+		t.Errorf("backoffFor(100) = %v, want capped at %v", got, maxBackoff)
+	}
+}